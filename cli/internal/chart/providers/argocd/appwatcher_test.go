@@ -0,0 +1,112 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/flamingo/openframe/internal/chart/utils/config"
+)
+
+// newFakeApplication builds a minimal Application object as it would come
+// back from a watch/list against applications.argoproj.io.
+func newFakeApplication(name, health, sync, phase string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "argocd",
+		},
+		"status": map[string]interface{}{
+			"health":         map[string]interface{}{"status": health},
+			"sync":           map[string]interface{}{"status": sync},
+			"operationState": map[string]interface{}{"phase": phase},
+		},
+	}}
+}
+
+func newFakeWatchManager(objs ...runtime.Object) *Manager {
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, map[schema.GroupVersionResource]string{
+		applicationsGVR: "ApplicationList",
+	}, objs...)
+	return NewManagerWithWatch(client)
+}
+
+func newTestAppWatcherRunnable(manager *Manager, cfg config.ChartInstallConfig) *appWatcherRunnable {
+	a := newAppWatcherRunnable(manager, cfg, newSpinnerRunnable(true))
+	a.timeout = time.Second
+	a.checkInterval = 20 * time.Millisecond
+	return a
+}
+
+func TestAppWatcherRunnable_FailFastOnSyncError(t *testing.T) {
+	manager := newFakeWatchManager(newFakeApplication("guestbook", "Degraded", "OutOfSync", "Failed"))
+	a := newTestAppWatcherRunnable(manager, config.ChartInstallConfig{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	err := a.Start(ctx)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "sync failed")
+}
+
+func TestAppWatcherRunnable_BecomesReadyWhenAppHealthy(t *testing.T) {
+	manager := newFakeWatchManager(newFakeApplication("guestbook", "Healthy", "Synced", ""))
+	a := newTestAppWatcherRunnable(manager, config.ChartInstallConfig{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// Start reports success through Ready() and keeps running until ctx ends.
+	err := a.Start(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, a.Ready())
+}
+
+func TestAppWatcherRunnable_AllowEventualConsistencySkipsFailFast(t *testing.T) {
+	manager := newFakeWatchManager(newFakeApplication("guestbook", "Degraded", "OutOfSync", "Failed"))
+	cfg := config.ChartInstallConfig{AllowEventualConsistency: true}
+	a := newTestAppWatcherRunnable(manager, cfg)
+	a.timeout = 50 * time.Millisecond
+
+	err := a.Start(context.Background())
+	assert.ErrorContains(t, err, "timeout waiting for ArgoCD applications",
+		"AllowEventualConsistency must skip the fail-fast abort and keep waiting until the overall timeout")
+}
+
+func TestAppWatcherRunnable_TimesOutWhenNeverReady(t *testing.T) {
+	manager := newFakeWatchManager(newFakeApplication("guestbook", "Progressing", "OutOfSync", ""))
+	a := newTestAppWatcherRunnable(manager, config.ChartInstallConfig{})
+	a.timeout = 50 * time.Millisecond
+
+	err := a.Start(context.Background())
+	assert.ErrorContains(t, err, "timeout waiting for ArgoCD applications")
+}
+
+func TestAppWatcherRunnable_StallStrictAbortsWithoutNewWatchEvents(t *testing.T) {
+	manager := newFakeWatchManager(newFakeApplication("guestbook", "Progressing", "OutOfSync", ""))
+	cfg := config.ChartInstallConfig{
+		PerAppStallTimeout: 10 * time.Millisecond,
+		StallPolicy:        config.StallPolicyStrict,
+	}
+	a := newTestAppWatcherRunnable(manager, cfg)
+	a.checkInterval = 10 * time.Millisecond
+
+	// Never push another watch event for "guestbook" - the only way this
+	// test can observe a stall is the periodic re-evaluation against the
+	// last snapshot, since the watcher's own channel stays silent.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	err := a.Start(ctx)
+	assert.ErrorContains(t, err, "stalled")
+}
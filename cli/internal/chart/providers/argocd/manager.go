@@ -0,0 +1,164 @@
+package argocd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sync"
+
+	"github.com/flamingo/openframe/internal/chart/utils/config"
+	"k8s.io/client-go/dynamic"
+)
+
+// Manager drives ArgoCD application installs and readiness checks for a
+// single cluster context.
+type Manager struct {
+	// dynamicClient is used by applicationWatcher to watch
+	// applications.argoproj.io directly instead of shelling out to the
+	// argocd CLI on every poll. It may be nil, in which case
+	// WaitForApplications falls back to list-based polling only.
+	dynamicClient dynamic.Interface
+
+	progressMu sync.Mutex
+	progress   Progress
+}
+
+// NewManager creates an ArgoCD Manager that shells out to the argocd CLI.
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// NewManagerWithWatch creates an ArgoCD Manager that watches
+// applications.argoproj.io via the given dynamic client instead of polling
+// `argocd app list`, falling back to polling only when the watch drops.
+func NewManagerWithWatch(client dynamic.Interface) *Manager {
+	return &Manager{dynamicClient: client}
+}
+
+// AppCondition is a single entry from an Application's status.conditions.
+type AppCondition struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// AppResource is a single entry from an Application's status.resources[],
+// used to diagnose why an application is stalled.
+type AppResource struct {
+	Kind            string `json:"kind"`
+	Name            string `json:"name"`
+	Health          string `json:"health"`
+	HookPhase       string `json:"hookPhase"`
+	RequiresPruning bool   `json:"requiresPruning"`
+}
+
+// AppStatus is the parsed status of a single ArgoCD Application, as reported
+// by `argocd app list`.
+type AppStatus struct {
+	Name    string
+	Health  string
+	Sync    string
+	Phase   string
+	Message string
+
+	Conditions []AppCondition
+	Resources  []AppResource
+
+	// PruningRequired is the number of resources in status.resources[] that
+	// are marked as requiring pruning.
+	PruningRequired int
+
+	// IgnoreExtraneous mirrors the
+	// argocd.argoproj.io/sync-options: IgnoreExtraneous annotation on the
+	// Application, so extraneous/prunable resources don't block readiness.
+	IgnoreExtraneous bool
+}
+
+// rawApplication mirrors the subset of the ArgoCD Application CRD that
+// `argocd app list -o json` emits and that we care about here.
+type rawApplication struct {
+	Metadata struct {
+		Name        string            `json:"name"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Status struct {
+		Health struct {
+			Status string `json:"status"`
+		} `json:"health"`
+		Sync struct {
+			Status string `json:"status"`
+		} `json:"sync"`
+		OperationState struct {
+			Phase   string `json:"phase"`
+			Message string `json:"message"`
+		} `json:"operationState"`
+		Conditions []AppCondition `json:"conditions"`
+		Resources  []struct {
+			Kind            string `json:"kind"`
+			Name            string `json:"name"`
+			RequiresPruning bool   `json:"requiresPruning"`
+			HookPhase       string `json:"hookPhase"`
+			Health          struct {
+				Status string `json:"status"`
+			} `json:"health"`
+		} `json:"resources"`
+	} `json:"status"`
+}
+
+// parseApplications lists ArgoCD applications and parses their health, sync
+// and operation status.
+func (m *Manager) parseApplications(ctx context.Context, verbose bool) ([]AppStatus, error) {
+	cmd := exec.CommandContext(ctx, "argocd", "app", "list", "-o", "json")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ArgoCD applications: %w", err)
+	}
+
+	var raw []rawApplication
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse ArgoCD application list: %w", err)
+	}
+
+	apps := make([]AppStatus, 0, len(raw))
+	for _, r := range raw {
+		pruning := 0
+		resources := make([]AppResource, 0, len(r.Status.Resources))
+		for _, res := range r.Status.Resources {
+			if res.RequiresPruning {
+				pruning++
+			}
+			resources = append(resources, AppResource{
+				Kind:            res.Kind,
+				Name:            res.Name,
+				Health:          res.Health.Status,
+				HookPhase:       res.HookPhase,
+				RequiresPruning: res.RequiresPruning,
+			})
+		}
+
+		apps = append(apps, AppStatus{
+			Name:             r.Metadata.Name,
+			Health:           r.Status.Health.Status,
+			Sync:             r.Status.Sync.Status,
+			Phase:            r.Status.OperationState.Phase,
+			Message:          r.Status.OperationState.Message,
+			Conditions:       r.Status.Conditions,
+			Resources:        resources,
+			PruningRequired:  pruning,
+			IgnoreExtraneous: r.Metadata.Annotations["argocd.argoproj.io/sync-options"] == "IgnoreExtraneous",
+		})
+	}
+
+	return apps, nil
+}
+
+// getTotalExpectedApplications returns the number of applications that
+// `argocd app list` currently reports, or 0 if the list fails or is empty
+// (the caller then falls back to tracking the observed high water mark).
+func (m *Manager) getTotalExpectedApplications(ctx context.Context, config config.ChartInstallConfig) int {
+	apps, err := m.parseApplications(ctx, config.Verbose)
+	if err != nil {
+		return 0
+	}
+	return len(apps)
+}
@@ -0,0 +1,97 @@
+package argocd
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// speedEstimatorMinWindow is the minimum span of samples required before
+	// an ETA is published, to avoid noisy early estimates.
+	speedEstimatorMinWindow = 1 * time.Minute
+	// speedEstimatorMaxWindow caps how far back samples are kept.
+	speedEstimatorMaxWindow = 30 * time.Minute
+)
+
+// speedSample is a single (timestamp, remaining) observation used to derive
+// a throughput estimate over a sliding window.
+type speedSample struct {
+	at        time.Time
+	remaining int
+}
+
+// speedEstimator tracks a bounded history of "remaining work" samples and
+// derives a throughput (apps/minute) and ETA from the oldest and newest
+// samples still inside the window. The window grows from zero up to
+// speedEstimatorMaxWindow as samples accumulate.
+type speedEstimator struct {
+	mu      sync.Mutex
+	samples []speedSample
+}
+
+// record appends a new (now, remaining) sample and evicts samples older than
+// speedEstimatorMaxWindow.
+func (e *speedEstimator) record(now time.Time, remaining int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.samples = append(e.samples, speedSample{at: now, remaining: remaining})
+
+	cutoff := now.Add(-speedEstimatorMaxWindow)
+	for len(e.samples) > 0 && e.samples[0].at.Before(cutoff) {
+		e.samples = e.samples[1:]
+	}
+}
+
+// estimate returns the current throughput (apps/minute) and ETA based on the
+// oldest and newest samples in the window. ok is false until at least
+// speedEstimatorMinWindow worth of history has accumulated.
+func (e *speedEstimator) estimate() (speedPerMin float64, eta time.Duration, stalled bool, ok bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.samples) < 2 {
+		return 0, 0, false, false
+	}
+
+	oldest := e.samples[0]
+	newest := e.samples[len(e.samples)-1]
+
+	span := newest.at.Sub(oldest.at)
+	if span < speedEstimatorMinWindow {
+		return 0, 0, false, false
+	}
+
+	speed := float64(oldest.remaining-newest.remaining) / span.Minutes()
+	if speed <= 0 {
+		return speed, 0, true, true
+	}
+
+	etaMinutes := float64(newest.remaining) / speed
+	return speed, time.Duration(etaMinutes * float64(time.Minute)), false, true
+}
+
+// Progress is a point-in-time snapshot of an app-of-apps rollout, suitable
+// for rendering by the spinner or by other callers (e.g. status commands).
+type Progress struct {
+	Ready       int
+	Total       int
+	SpeedPerMin float64
+	ETA         time.Duration
+	Stalled     bool
+}
+
+// Progress returns the most recently observed rollout progress. It is safe
+// to call concurrently with WaitForApplications.
+func (m *Manager) Progress() Progress {
+	m.progressMu.Lock()
+	defer m.progressMu.Unlock()
+	return m.progress
+}
+
+// setProgress updates the progress snapshot returned by Progress().
+func (m *Manager) setProgress(p Progress) {
+	m.progressMu.Lock()
+	defer m.progressMu.Unlock()
+	m.progress = p
+}
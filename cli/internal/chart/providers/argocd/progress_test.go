@@ -0,0 +1,90 @@
+package argocd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpeedEstimator_NotEnoughSamples(t *testing.T) {
+	e := &speedEstimator{}
+
+	_, _, _, ok := e.estimate()
+	assert.False(t, ok, "no samples recorded yet")
+
+	e.record(time.Unix(0, 0), 10)
+	_, _, _, ok = e.estimate()
+	assert.False(t, ok, "a single sample can't derive a rate")
+}
+
+func TestSpeedEstimator_BelowMinWindow(t *testing.T) {
+	e := &speedEstimator{}
+	start := time.Unix(0, 0)
+
+	e.record(start, 10)
+	e.record(start.Add(speedEstimatorMinWindow/2), 8)
+
+	_, _, _, ok := e.estimate()
+	assert.False(t, ok, "span is below speedEstimatorMinWindow")
+}
+
+func TestSpeedEstimator_Estimate(t *testing.T) {
+	e := &speedEstimator{}
+	start := time.Unix(0, 0)
+
+	e.record(start, 10)
+	e.record(start.Add(2*time.Minute), 6)
+
+	speedPerMin, eta, stalled, ok := e.estimate()
+	assert.True(t, ok)
+	assert.False(t, stalled)
+	assert.InDelta(t, 2.0, speedPerMin, 0.0001)
+	assert.Equal(t, 3*time.Minute, eta)
+}
+
+func TestSpeedEstimator_StalledOnNoProgress(t *testing.T) {
+	e := &speedEstimator{}
+	start := time.Unix(0, 0)
+
+	e.record(start, 10)
+	e.record(start.Add(2*time.Minute), 10)
+
+	speedPerMin, eta, stalled, ok := e.estimate()
+	assert.True(t, ok)
+	assert.True(t, stalled)
+	assert.Equal(t, time.Duration(0), eta)
+	assert.Equal(t, 0.0, speedPerMin)
+}
+
+func TestSpeedEstimator_StalledOnNegativeSpeed(t *testing.T) {
+	e := &speedEstimator{}
+	start := time.Unix(0, 0)
+
+	// remaining went up (e.g. new applications appeared), so throughput is
+	// negative - this must report stalled rather than a negative ETA.
+	e.record(start, 5)
+	e.record(start.Add(2*time.Minute), 8)
+
+	speedPerMin, eta, stalled, ok := e.estimate()
+	assert.True(t, ok)
+	assert.True(t, stalled)
+	assert.Equal(t, time.Duration(0), eta)
+	assert.Less(t, speedPerMin, 0.0)
+}
+
+func TestSpeedEstimator_WindowGrowsThenEvicts(t *testing.T) {
+	e := &speedEstimator{}
+	start := time.Unix(0, 0)
+
+	e.record(start, 100)
+	e.record(start.Add(speedEstimatorMaxWindow+time.Minute), 50)
+
+	e.mu.Lock()
+	samples := len(e.samples)
+	oldest := e.samples[0].remaining
+	e.mu.Unlock()
+
+	assert.Equal(t, 1, samples, "the sample older than speedEstimatorMaxWindow should be evicted")
+	assert.Equal(t, 50, oldest)
+}
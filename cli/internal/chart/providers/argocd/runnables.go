@@ -0,0 +1,543 @@
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/flamingo/openframe/internal/chart/utils/config"
+	"github.com/pterm/pterm"
+)
+
+// defaultPerAppStallTimeout is how long an application may sit in a
+// non-Healthy state without a status transition before appWatcherRunnable
+// considers it stalled, when config.PerAppStallTimeout is unset.
+const defaultPerAppStallTimeout = 15 * time.Minute
+
+// stallReannounceInterval is the minimum time between repeated stall
+// warnings (and the diagnosis re-fetch that comes with them) for the same
+// application, so a stuck app doesn't re-warn on every poll/watch tick.
+const stallReannounceInterval = 5 * time.Minute
+
+// bootstrapRunnable waits out the fixed grace period after an app-of-apps
+// install before applications are expected to exist, so the group's
+// readiness gate can't flip before ArgoCD has had a chance to create them.
+type bootstrapRunnable struct {
+	duration time.Duration
+	ready    atomic.Bool
+}
+
+func newBootstrapRunnable(duration time.Duration) *bootstrapRunnable {
+	return &bootstrapRunnable{duration: duration}
+}
+
+func (b *bootstrapRunnable) Start(ctx context.Context) error {
+	timer := time.NewTimer(b.duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+	}
+
+	b.ready.Store(true)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (b *bootstrapRunnable) Ready() bool {
+	return b.ready.Load()
+}
+
+// signalRunnable listens for Ctrl+C/SIGTERM and cancels the wait on receipt,
+// so WaitForApplications responds immediately even if the parent context's
+// own cancellation is slower to propagate.
+type signalRunnable struct {
+	cancel context.CancelFunc
+}
+
+func newSignalRunnable(cancel context.CancelFunc) *signalRunnable {
+	return &signalRunnable{cancel: cancel}
+}
+
+func (s *signalRunnable) Start(ctx context.Context) error {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-sigChan:
+		s.cancel()
+		return fmt.Errorf("operation cancelled: %w", ctx.Err())
+	}
+}
+
+func (s *signalRunnable) Ready() bool {
+	return true
+}
+
+// spinnerRunnable owns the pterm spinner (or, in silent mode, a single info
+// line) for the lifetime of a WaitForApplications call. It has no readiness
+// condition of its own; it exists purely to keep the spinner's lifecycle
+// tied to the group.
+type spinnerRunnable struct {
+	silent bool
+
+	mu      sync.Mutex
+	spinner *pterm.SpinnerPrinter
+	stopped bool
+}
+
+func newSpinnerRunnable(silent bool) *spinnerRunnable {
+	return &spinnerRunnable{silent: silent}
+}
+
+func (s *spinnerRunnable) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.silent {
+		s.spinner, _ = pterm.DefaultSpinner.
+			WithRemoveWhenDone(false).
+			WithShowTimer(true).
+			Start("Installing ArgoCD applications...")
+	} else {
+		pterm.Info.Println("Installing ArgoCD applications...")
+	}
+	s.mu.Unlock()
+
+	<-ctx.Done()
+	s.stop()
+	return ctx.Err()
+}
+
+func (s *spinnerRunnable) Ready() bool {
+	return true
+}
+
+func (s *spinnerRunnable) update(text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stopped && s.spinner != nil && s.spinner.IsActive {
+		s.spinner.UpdateText(text)
+	}
+}
+
+func (s *spinnerRunnable) fail(text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stopped && s.spinner != nil && s.spinner.IsActive {
+		s.spinner.Fail(text)
+		s.stopped = true
+	}
+}
+
+func (s *spinnerRunnable) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stopped && s.spinner != nil && s.spinner.IsActive {
+		s.spinner.Stop()
+		s.stopped = true
+	}
+}
+
+// appWatcherRunnable watches (or polls) ArgoCD application status and
+// becomes ready once every detected application is Healthy, Synced, and
+// free of outstanding pruning. It fails fast on Failed/Error phases and
+// SyncError/ComparisonError conditions unless config.AllowEventualConsistency
+// is set, and on the 60 minute overall timeout.
+type appWatcherRunnable struct {
+	manager *Manager
+	config  config.ChartInstallConfig
+	spinner *spinnerRunnable
+
+	timeout       time.Duration
+	checkInterval time.Duration
+
+	ready atomic.Bool
+}
+
+func newAppWatcherRunnable(m *Manager, cfg config.ChartInstallConfig, spinner *spinnerRunnable) *appWatcherRunnable {
+	return &appWatcherRunnable{
+		manager:       m,
+		config:        cfg,
+		spinner:       spinner,
+		timeout:       60 * time.Minute,
+		checkInterval: 2 * time.Second,
+	}
+}
+
+func (a *appWatcherRunnable) Ready() bool {
+	return a.ready.Load()
+}
+
+func (a *appWatcherRunnable) Start(ctx context.Context) error {
+	startTime := time.Now()
+	lastCheck := time.Now()
+
+	totalAppsExpected := a.manager.getTotalExpectedApplications(ctx, a.config)
+	if totalAppsExpected == 0 {
+		totalAppsExpected = -1
+	}
+
+	maxAppsSeenTotal := 0
+	maxAppsSeenReady := 0
+	everReadyApps := make(map[string]bool)
+	estimator := &speedEstimator{}
+	lastLoggedSecond := -1
+
+	stallTimeout := a.config.PerAppStallTimeout
+	if stallTimeout <= 0 {
+		stallTimeout = defaultPerAppStallTimeout
+	}
+	appTransitions := make(map[string]*appTransition)
+
+	// When the Manager has a dynamic client, watch applications.argoproj.io
+	// directly instead of forking `argocd app list` on every tick. The
+	// watcher falls back to list-based polling itself on disconnect, so in
+	// watch mode checkInterval only paces the re-evaluation of the last
+	// snapshot for stall detection; without a dynamic client it paces polls.
+	var watcherChan <-chan map[string]AppStatus
+	if a.manager.dynamicClient != nil {
+		watcher := newApplicationWatcher(a.manager.dynamicClient, a.manager, a.config.Verbose)
+		watcherChan = watcher.snapshots
+		go func() {
+			_ = watcher.Start(ctx)
+		}()
+	}
+
+	// evaluate processes one observed set of application statuses (from
+	// either the watcher or a poll) and reports whether the rollout has
+	// failed outright. Reaching "all ready" sets a.ready instead of
+	// returning, since completion is expressed through the group's
+	// readiness gate rather than a return value.
+	evaluate := func(apps []AppStatus) error {
+		totalApps := len(apps)
+		if totalApps > maxAppsSeenTotal {
+			maxAppsSeenTotal = totalApps
+			if a.config.Verbose && totalApps > 0 {
+				pterm.Info.Printf("Detected %d ArgoCD applications to synchronize\n", totalApps)
+			}
+		}
+
+		if totalAppsExpected == -1 || maxAppsSeenTotal > totalAppsExpected {
+			totalAppsExpected = maxAppsSeenTotal
+		}
+
+		currentHealthyCount := 0
+		currentlyReady := 0
+		healthyApps := make([]string, 0)
+		syncedApps := make([]string, 0)
+		notReadyApps := make([]string, 0)
+
+		var failedApps []string
+		pruningRequired := 0
+
+		for _, app := range apps {
+			if app.Health == "Healthy" {
+				currentHealthyCount++
+				healthyApps = append(healthyApps, app.Name)
+			}
+
+			if app.Sync == "Synced" {
+				syncedApps = append(syncedApps, app.Name)
+			}
+
+			if !app.IgnoreExtraneous {
+				pruningRequired += app.PruningRequired
+			}
+
+			// Surface hard failures immediately instead of looping until
+			// the overall timeout.
+			if app.Phase == "Failed" || app.Phase == "Error" {
+				failedApps = append(failedApps, fmt.Sprintf("%s (phase=%s: %s)", app.Name, app.Phase, app.Message))
+			}
+			for _, cond := range app.Conditions {
+				if cond.Type == "SyncError" || cond.Type == "ComparisonError" {
+					failedApps = append(failedApps, fmt.Sprintf("%s (%s: %s)", app.Name, cond.Type, cond.Message))
+				}
+			}
+
+			// An app is ready once it's healthy and synced, with no
+			// outstanding pruning unless it opted out via the
+			// IgnoreExtraneous sync option.
+			ready := app.Health == "Healthy" && app.Sync == "Synced" && (app.IgnoreExtraneous || app.PruningRequired == 0)
+			if ready {
+				currentlyReady++
+				everReadyApps[app.Name] = true
+				delete(appTransitions, app.Name)
+			} else if app.Health != "Healthy" || app.Sync != "Synced" {
+				var status string
+				if app.Health != "Healthy" && app.Sync != "Synced" {
+					status = fmt.Sprintf("%s/%s", app.Health, app.Sync)
+				} else if app.Health != "Healthy" {
+					status = fmt.Sprintf("Health: %s", app.Health)
+				} else {
+					status = fmt.Sprintf("Sync: %s", app.Sync)
+				}
+				notReadyApps = append(notReadyApps, fmt.Sprintf("%s (%s)", app.Name, status))
+			}
+
+			if app.Health == "Healthy" {
+				delete(appTransitions, app.Name)
+			} else {
+				if stalledSince, history, isStalled := trackTransition(appTransitions, app, stallTimeout); isStalled {
+					diagnosis := a.diagnoseStalledApp(ctx, app)
+					pterm.Warning.Printf("Application %s has been stalled in %s since %s ago: %s\n",
+						app.Name, history, time.Since(stalledSince).Round(time.Second), diagnosis)
+
+					if a.config.StallPolicy == config.StallPolicyStrict {
+						a.spinner.fail(fmt.Sprintf("Application %s stalled", app.Name))
+						return fmt.Errorf("application %s stalled in %s for %s: %s",
+							app.Name, history, time.Since(stalledSince).Round(time.Second), diagnosis)
+					}
+				}
+			}
+		}
+
+		if len(failedApps) > 0 && !a.config.AllowEventualConsistency {
+			a.spinner.fail("ArgoCD application sync failed")
+			return fmt.Errorf("ArgoCD application sync failed: %v", failedApps)
+		}
+
+		remaining := totalApps - currentlyReady
+		if remaining < 0 {
+			remaining = 0
+		}
+		estimator.record(time.Now(), remaining)
+		speedPerMin, eta, stalled, haveEstimate := estimator.estimate()
+		a.manager.setProgress(Progress{
+			Ready:       currentlyReady,
+			Total:       totalApps,
+			SpeedPerMin: speedPerMin,
+			ETA:         eta,
+			Stalled:     stalled,
+		})
+
+		if a.config.Verbose && totalApps > 0 {
+			elapsed := time.Since(startTime)
+
+			progress := ""
+			progressPercent := float64(currentlyReady) / float64(totalApps) * 100
+			progress = fmt.Sprintf(" (%.0f%%)", progressPercent)
+
+			etaText := ""
+			if haveEstimate {
+				if stalled {
+					etaText = " [stalled]"
+				} else {
+					etaText = fmt.Sprintf(" [ETA %s]", eta.Round(time.Second))
+				}
+			}
+			a.spinner.update(fmt.Sprintf("Installing ArgoCD applications... %d/%d ready%s [%s]%s",
+				currentlyReady, totalApps, progress, elapsed.Round(time.Second), etaText))
+
+			// Only show detailed status on 10-second marks to avoid spam.
+			// Besides the modulo check, track the last logged second
+			// explicitly, since evaluate can now run multiple times within
+			// the same second (e.g. a burst of watch events) and would
+			// otherwise print the block once per call on that second.
+			elapsedSeconds := int(elapsed.Seconds())
+			if elapsedSeconds%10 == 0 && elapsedSeconds != lastLoggedSecond {
+				lastLoggedSecond = elapsedSeconds
+				pterm.Info.Printf("ArgoCD Sync Progress: %d/%d applications ready (%s elapsed)\n",
+					currentlyReady, totalApps, elapsed.Round(time.Second))
+
+				if pruningRequired > 0 {
+					pterm.Info.Printf("  %d resource(s) pending pruning\n", pruningRequired)
+				}
+
+				if len(notReadyApps) > 0 {
+					if len(notReadyApps) <= 8 {
+						pterm.Info.Printf("  Still waiting for: %v\n", notReadyApps)
+					} else {
+						pterm.Info.Printf("  Still waiting for %d applications (showing first 5): %v...\n",
+							len(notReadyApps), notReadyApps[:5])
+					}
+				}
+
+				if len(healthyApps) > 0 && len(healthyApps) <= 5 {
+					startIdx := 0
+					if len(healthyApps) > 5 {
+						startIdx = len(healthyApps) - 5
+					}
+					pterm.Debug.Printf("  Recently completed: %v\n", healthyApps[startIdx:])
+				}
+			}
+		}
+
+		readyCount := len(everReadyApps)
+		if readyCount > maxAppsSeenReady {
+			maxAppsSeenReady = readyCount
+		}
+		if currentlyReady > maxAppsSeenReady {
+			maxAppsSeenReady = currentlyReady
+		}
+
+		if totalApps > 0 && currentlyReady == totalApps && !a.ready.Load() {
+			a.spinner.stop()
+			pterm.Success.Println("All ArgoCD applications installed")
+			a.ready.Store(true)
+		}
+
+		return nil
+	}
+
+	var lastSnapshot []AppStatus
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case snapshot := <-watcherChan:
+			if a.ready.Load() {
+				continue
+			}
+			apps := make([]AppStatus, 0, len(snapshot))
+			for _, app := range snapshot {
+				apps = append(apps, app)
+			}
+			lastSnapshot = apps
+			if err := evaluate(apps); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if a.ready.Load() {
+				continue
+			}
+			if time.Since(startTime) > a.timeout {
+				a.spinner.fail(fmt.Sprintf("Timeout after %v", a.timeout))
+				return fmt.Errorf("timeout waiting for ArgoCD applications after %v", a.timeout)
+			}
+
+			if time.Since(lastCheck) < a.checkInterval {
+				continue
+			}
+			lastCheck = time.Now()
+
+			// In watch mode the channel only wakes us on a status change, so
+			// a stuck app that stops emitting events (a hook that never
+			// finishes, a missing CRD, an image pull stuck in backoff) would
+			// otherwise never be re-checked against the stall timeout until
+			// the unrelated overall timeout. Re-evaluate the last snapshot we
+			// have on this interval as well, purely to re-run stall
+			// detection; fresh data still arrives through watcherChan.
+			if watcherChan != nil {
+				if lastSnapshot == nil {
+					continue
+				}
+				if err := evaluate(lastSnapshot); err != nil {
+					return err
+				}
+				continue
+			}
+
+			apps, err := a.manager.parseApplications(ctx, a.config.Verbose)
+			if err != nil {
+				continue
+			}
+
+			lastSnapshot = apps
+			if err := evaluate(apps); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// appTransition tracks when an application's (Health, Sync, Phase) tuple
+// last changed, so appWatcherRunnable can tell "still Progressing because
+// it just started" apart from "stuck Progressing for 20 minutes".
+type appTransition struct {
+	firstSeen    time.Time
+	lastChangeAt time.Time
+	lastKey      string
+	lastWarnedAt time.Time
+}
+
+// trackTransition records app's current status against its transition
+// history and reports whether it has been stalled (no status change) for
+// longer than stallTimeout. When isStalled is true, stalledSince is when the
+// status last changed and history describes that status. Once an app is
+// reported stalled, isStalled only goes true again after
+// stallReannounceInterval has passed, so callers that warn on every true
+// don't re-warn on every tick while the app remains stuck.
+func trackTransition(transitions map[string]*appTransition, app AppStatus, stallTimeout time.Duration) (stalledSince time.Time, history string, isStalled bool) {
+	key := fmt.Sprintf("health=%s sync=%s phase=%s", app.Health, app.Sync, app.Phase)
+	now := time.Now()
+
+	t, seen := transitions[app.Name]
+	if !seen {
+		transitions[app.Name] = &appTransition{firstSeen: now, lastChangeAt: now, lastKey: key}
+		return time.Time{}, key, false
+	}
+
+	if t.lastKey != key {
+		t.lastKey = key
+		t.lastChangeAt = now
+		t.lastWarnedAt = time.Time{}
+		return time.Time{}, key, false
+	}
+
+	if now.Sub(t.lastChangeAt) > stallTimeout && now.Sub(t.lastWarnedAt) > stallReannounceInterval {
+		t.lastWarnedAt = now
+		return t.lastChangeAt, t.lastKey, true
+	}
+
+	return time.Time{}, key, false
+}
+
+// diagnoseStalledApp fetches a fresh copy of app's status via
+// parseApplications and summarizes why it might be stuck: degraded pods,
+// hooks that haven't completed, and any SyncError/ComparisonError condition.
+func (a *appWatcherRunnable) diagnoseStalledApp(ctx context.Context, app AppStatus) string {
+	fresh := app
+	if apps, err := a.manager.parseApplications(ctx, a.config.Verbose); err == nil {
+		for _, candidate := range apps {
+			if candidate.Name == app.Name {
+				fresh = candidate
+				break
+			}
+		}
+	}
+
+	var lines []string
+
+	var degradedPods []string
+	var pendingHooks []string
+	for _, res := range fresh.Resources {
+		if res.Kind == "Pod" && res.Health == "Degraded" {
+			degradedPods = append(degradedPods, res.Name)
+		}
+		if res.HookPhase != "" && res.HookPhase != "Succeeded" {
+			pendingHooks = append(pendingHooks, fmt.Sprintf("%s (%s)", res.Name, res.HookPhase))
+		}
+	}
+	if len(degradedPods) > 0 {
+		lines = append(lines, fmt.Sprintf("Degraded pods: %v", degradedPods))
+	}
+	if len(pendingHooks) > 0 {
+		lines = append(lines, fmt.Sprintf("Hook phase: %v", pendingHooks))
+	}
+
+	for _, cond := range fresh.Conditions {
+		if cond.Type == "SyncError" || cond.Type == "ComparisonError" {
+			lines = append(lines, fmt.Sprintf("%s: %s", cond.Type, cond.Message))
+		}
+	}
+
+	if len(lines) == 0 {
+		return "no further diagnosis available from status.resources/conditions"
+	}
+	return strings.Join(lines, "; ")
+}
@@ -0,0 +1,68 @@
+package argocd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackTransition_FirstSeenIsNotStalled(t *testing.T) {
+	transitions := make(map[string]*appTransition)
+	app := AppStatus{Name: "my-app", Health: "Progressing"}
+
+	_, _, isStalled := trackTransition(transitions, app, time.Minute)
+	assert.False(t, isStalled)
+}
+
+func TestTrackTransition_StatusChangeResetsTimer(t *testing.T) {
+	transitions := make(map[string]*appTransition)
+	app := AppStatus{Name: "my-app", Health: "Progressing"}
+	trackTransition(transitions, app, time.Millisecond)
+
+	transitions["my-app"].lastChangeAt = time.Now().Add(-time.Hour)
+
+	app.Health = "Degraded"
+	_, _, isStalled := trackTransition(transitions, app, time.Millisecond)
+	assert.False(t, isStalled, "a status change should reset the stall clock")
+}
+
+func TestTrackTransition_StalledAfterTimeout(t *testing.T) {
+	transitions := make(map[string]*appTransition)
+	app := AppStatus{Name: "my-app", Health: "Progressing"}
+	trackTransition(transitions, app, time.Millisecond)
+
+	transitions["my-app"].lastChangeAt = time.Now().Add(-time.Hour)
+
+	_, history, isStalled := trackTransition(transitions, app, time.Millisecond)
+	assert.True(t, isStalled)
+	assert.Contains(t, history, "health=Progressing")
+}
+
+func TestTrackTransition_DoesNotReannounceEveryTick(t *testing.T) {
+	transitions := make(map[string]*appTransition)
+	app := AppStatus{Name: "my-app", Health: "Progressing"}
+	trackTransition(transitions, app, time.Millisecond)
+	transitions["my-app"].lastChangeAt = time.Now().Add(-time.Hour)
+
+	_, _, first := trackTransition(transitions, app, time.Millisecond)
+	assert.True(t, first, "first check past the stall timeout should warn")
+
+	_, _, second := trackTransition(transitions, app, time.Millisecond)
+	assert.False(t, second, "a re-check immediately after should not warn again")
+}
+
+func TestTrackTransition_ReannouncesAfterInterval(t *testing.T) {
+	transitions := make(map[string]*appTransition)
+	app := AppStatus{Name: "my-app", Health: "Progressing"}
+	trackTransition(transitions, app, time.Millisecond)
+	transitions["my-app"].lastChangeAt = time.Now().Add(-time.Hour)
+
+	_, _, first := trackTransition(transitions, app, time.Millisecond)
+	assert.True(t, first)
+
+	transitions["my-app"].lastWarnedAt = time.Now().Add(-stallReannounceInterval - time.Second)
+
+	_, _, second := trackTransition(transitions, app, time.Millisecond)
+	assert.True(t, second, "a check past stallReannounceInterval since the last warning should warn again")
+}
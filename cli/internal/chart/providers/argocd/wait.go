@@ -2,29 +2,35 @@ package argocd
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
-	"os/signal"
-	"sync"
-	"syscall"
 	"time"
 
+	"github.com/flamingo/openframe/internal/chart/utils/argocd/waitgroup"
 	"github.com/flamingo/openframe/internal/chart/utils/config"
 	"github.com/pterm/pterm"
 )
 
-// WaitForApplications waits for all ArgoCD applications to be Healthy and Synced
+// bootstrapGrace is how long WaitForApplications waits after an
+// app-of-apps install before applications are expected to exist.
+const bootstrapGrace = 30 * time.Second
+
+// WaitForApplications waits for all ArgoCD applications to be Healthy and
+// Synced. It coordinates a bootstrap grace period, signal handling, the
+// spinner UI, and application status watching as independent runnables in a
+// waitgroup.Group, returning once the group's readiness gate flips (i.e.
+// every runnable is ready at once).
 func (m *Manager) WaitForApplications(ctx context.Context, config config.ChartInstallConfig) error {
 	// Skip waiting in dry-run mode for testing
 	if config.DryRun {
 		return nil
 	}
-	
+
 	// Check if already cancelled before starting
 	if ctx.Err() != nil {
 		return fmt.Errorf("operation already cancelled: %w", ctx.Err())
 	}
-	
+
 	// Early exit if context has a short deadline (indicates timeout scenario)
 	if deadline, ok := ctx.Deadline(); ok {
 		if time.Until(deadline) < 5*time.Second {
@@ -32,50 +38,13 @@ func (m *Manager) WaitForApplications(ctx context.Context, config config.ChartIn
 			return nil
 		}
 	}
-	
-	// Create a derived context that responds to both parent cancellation AND direct signals
-	// This ensures immediate response to Ctrl+C even if parent context isn't propagating fast enough
+
+	// Create a derived context that responds to both parent cancellation AND
+	// direct signals. This ensures immediate response to Ctrl+C even if the
+	// parent context isn't propagating fast enough.
 	localCtx, localCancel := context.WithCancel(ctx)
 	defer localCancel()
-	
-	// Handle direct interrupt signals
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	defer signal.Stop(sigChan)
-	
-	go func() {
-		<-sigChan
-		localCancel() // Cancel our local context immediately
-	}()
-	
-	
-	// Check if we should start the spinner (skip if context is cancelled or expiring soon)
-	shouldSkipSpinner := false
-	
-	// Check if context is cancelled
-	if localCtx.Err() != nil {
-		shouldSkipSpinner = true
-	}
-	
-	// Check if original context is cancelled
-	if ctx.Err() != nil {
-		shouldSkipSpinner = true
-	}
-	
-	// Check if context deadline is very close (less than 10 seconds)
-	if deadline, ok := ctx.Deadline(); ok {
-		timeLeft := time.Until(deadline)
-		if timeLeft < 10*time.Second {
-			shouldSkipSpinner = true
-		}
-	}
-	
-	if shouldSkipSpinner {
-		// Context is cancelled or expiring soon - skip ArgoCD applications wait entirely
-		return nil
-	}
-	
-	// Show initial verbose info if enabled
+
 	if config.Verbose {
 		pterm.Info.Println("Starting ArgoCD application synchronization...")
 		pterm.Debug.Println("  - Waiting for applications to be created by app-of-apps")
@@ -83,235 +52,19 @@ func (m *Manager) WaitForApplications(ctx context.Context, config config.ChartIn
 		pterm.Debug.Println("  - Progress updates every 10 seconds in verbose mode")
 	}
 
-	// Start pterm spinner only if not in silent/non-interactive mode
-	var spinner *pterm.SpinnerPrinter
-	if !config.Silent {
-		spinner, _ = pterm.DefaultSpinner.
-			WithRemoveWhenDone(false).
-			WithShowTimer(true).
-			Start("Installing ArgoCD applications...")
-	} else {
-		// In non-interactive mode, just show a simple info message
-		pterm.Info.Println("Installing ArgoCD applications...")
-	}
-	
-	var spinnerMutex sync.Mutex
-	spinnerStopped := false
-	
-	// Function to stop spinner safely
-	stopSpinner := func() {
-		spinnerMutex.Lock()
-		defer spinnerMutex.Unlock()
-		if !spinnerStopped && spinner != nil && spinner.IsActive {
-			spinner.Stop()
-			spinnerStopped = true
-		}
-	}
-	
-	// Monitor for context cancellation (includes interrupt signals from parent or direct signals)
-	go func() {
-		<-localCtx.Done()
-		stopSpinner()
-	}()
-	
-	// Ensure spinner is stopped when function exits
-	defer stopSpinner()
-	
-	// Bootstrap wait (30 seconds)
-	bootstrapEnd := time.Now().Add(30 * time.Second)
-	
-	// Check every 10ms for immediate response
-	ticker := time.NewTicker(10 * time.Millisecond)
-	defer ticker.Stop()
-	
-	// Bootstrap phase
-	for time.Now().Before(bootstrapEnd) {
-		select {
-		case <-localCtx.Done():
-			return fmt.Errorf("operation cancelled: %w", localCtx.Err())
-		case <-ticker.C:
-			// Continue waiting
-		}
-	}
-	
-	// Main monitoring phase
-	startTime := time.Now()
-	timeout := 60 * time.Minute
-	checkInterval := 2 * time.Second
-	lastCheck := time.Now()
-	
-	// Get expected applications count
-	totalAppsExpected := m.getTotalExpectedApplications(localCtx, config)
-	if totalAppsExpected == 0 {
-		totalAppsExpected = -1
-	}
-	
-	maxAppsSeenTotal := 0
-	maxAppsSeenReady := 0
-	
-	// Track applications that have ever been ready (healthy + synced) during this session
-	// Once an app is ready, it stays counted even if it temporarily goes out of sync
-	everReadyApps := make(map[string]bool)
-	
-	// Main loop
-	for {
-		select {
-		case <-localCtx.Done():
-			return fmt.Errorf("operation cancelled: %w", localCtx.Err())
-		case <-ticker.C:
-			// Check timeout
-			if time.Since(startTime) > timeout {
-				spinnerMutex.Lock()
-				if !spinnerStopped && spinner != nil && spinner.IsActive {
-					spinner.Fail(fmt.Sprintf("Timeout after %v", timeout))
-					spinnerStopped = true
-				}
-				spinnerMutex.Unlock()
-				return fmt.Errorf("timeout waiting for ArgoCD applications after %v", timeout)
-			}
-			
-			// Check applications every 2 seconds
-			if time.Since(lastCheck) < checkInterval {
-				continue
-			}
-			lastCheck = time.Now()
-			
-			// Parse applications
-			apps, err := m.parseApplications(localCtx, config.Verbose)
-			if err != nil {
-				if localCtx.Err() != nil {
-					return fmt.Errorf("operation cancelled: %w", localCtx.Err())
-				}
-				// Ignore parse errors and retry
-				continue
-			}
-
-			totalApps := len(apps)
-			if totalApps > maxAppsSeenTotal {
-				maxAppsSeenTotal = totalApps
-				// Show initial application count when first detected (verbose mode)
-				if config.Verbose && totalApps > 0 {
-					pterm.Info.Printf("Detected %d ArgoCD applications to synchronize\n", totalApps)
-				}
-			}
-
-			if totalAppsExpected == -1 || maxAppsSeenTotal > totalAppsExpected {
-				totalAppsExpected = maxAppsSeenTotal
-			}
-
-			// Track applications that have ever been ready during this session
-			currentHealthyCount := 0
-			currentlyReady := 0
-			healthyApps := make([]string, 0)
-			syncedApps := make([]string, 0)
-			notReadyApps := make([]string, 0)
-
-			for _, app := range apps {
-				// Count currently healthy apps for monitoring
-				if app.Health == "Healthy" {
-					currentHealthyCount++
-					healthyApps = append(healthyApps, app.Name)
-				}
-
-				if app.Sync == "Synced" {
-					syncedApps = append(syncedApps, app.Name)
-				}
-
-				// Count currently ready apps (both healthy and synced)
-				if app.Health == "Healthy" && app.Sync == "Synced" {
-					currentlyReady++
-					// Mark apps as "ever ready" if they are currently healthy and synced
-					// Once marked, they stay counted even if they go out of sync later
-					everReadyApps[app.Name] = true
-				} else {
-					// Track apps that are not yet ready with more detailed status
-					if app.Health != "Healthy" || app.Sync != "Synced" {
-						// Show the most important status issue
-						var status string
-						if app.Health != "Healthy" && app.Sync != "Synced" {
-							status = fmt.Sprintf("%s/%s", app.Health, app.Sync)
-						} else if app.Health != "Healthy" {
-							status = fmt.Sprintf("Health: %s", app.Health)
-						} else {
-							status = fmt.Sprintf("Sync: %s", app.Sync)
-						}
-						notReadyApps = append(notReadyApps, fmt.Sprintf("%s (%s)", app.Name, status))
-					}
-				}
-			}
-
-			// Show verbose logging if enabled
-			if config.Verbose && totalApps > 0 {
-				elapsed := time.Since(startTime)
-
-				// Update spinner message with current status
-				spinnerMutex.Lock()
-				if !spinnerStopped && spinner != nil && spinner.IsActive {
-					progress := ""
-					if totalApps > 0 {
-						progressPercent := float64(currentlyReady) / float64(totalApps) * 100
-						progress = fmt.Sprintf(" (%.0f%%)", progressPercent)
-					}
-					spinner.UpdateText(fmt.Sprintf("Installing ArgoCD applications... %d/%d ready%s [%s]",
-						currentlyReady, totalApps, progress, elapsed.Round(time.Second)))
-				}
-				spinnerMutex.Unlock()
-
-				// Only show detailed status every 10 seconds to avoid spam
-				if int(elapsed.Seconds())%10 == 0 {
-					pterm.Info.Printf("ArgoCD Sync Progress: %d/%d applications ready (%s elapsed)\n",
-						currentlyReady, totalApps, elapsed.Round(time.Second))
-
-					// Always show pending applications when there are any
-					if len(notReadyApps) > 0 {
-						if len(notReadyApps) <= 8 {
-							pterm.Info.Printf("  Still waiting for: %v\n", notReadyApps)
-						} else {
-							pterm.Info.Printf("  Still waiting for %d applications (showing first 5): %v...\n",
-								len(notReadyApps), notReadyApps[:5])
-						}
-					}
-
-					// Show recently completed applications
-					if len(healthyApps) > 0 && len(healthyApps) <= 5 {
-						startIdx := 0
-						if len(healthyApps) > 5 {
-							startIdx = len(healthyApps) - 5
-						}
-						pterm.Debug.Printf("  Recently completed: %v\n", healthyApps[startIdx:])
-					}
-				}
-			}
-			
-			// Use the high water mark of applications that have ever been ready
-			readyCount := len(everReadyApps)
+	spinner := newSpinnerRunnable(config.Silent)
 
-			if readyCount > maxAppsSeenReady {
-				maxAppsSeenReady = readyCount
-			}
-			
-			// Check if deployment is complete - ALL currently detected apps must be healthy and synced
-			// All apps must be currently ready (not just "ever ready")
-			allReady := false
-			if totalApps > 0 && currentlyReady == totalApps {
-				allReady = true
-			}
+	group := &waitgroup.Group{}
+	group.Add(newBootstrapRunnable(bootstrapGrace))
+	group.Add(newSignalRunnable(localCancel))
+	group.Add(spinner)
+	group.Add(newAppWatcherRunnable(m, config, spinner))
 
-			// Update ready count for display purposes (still use everReady for progress tracking)
-			if currentlyReady > maxAppsSeenReady {
-				maxAppsSeenReady = currentlyReady
-			}
-			
-			if allReady {
-				spinnerMutex.Lock()
-				if !spinnerStopped && spinner != nil && spinner.IsActive {
-					spinner.Stop()
-					spinnerStopped = true
-				}
-				spinnerMutex.Unlock()
-				pterm.Success.Println("All ArgoCD applications installed")
-				return nil
-			}
+	if err := group.Run(localCtx); err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("operation cancelled: %w", err)
 		}
+		return err
 	}
-}
\ No newline at end of file
+	return nil
+}
@@ -0,0 +1,239 @@
+package argocd
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kwatch "k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// applicationsGVR identifies the ArgoCD Application CRD watched by
+// applicationWatcher.
+var applicationsGVR = schema.GroupVersionResource{
+	Group:    "argoproj.io",
+	Version:  "v1alpha1",
+	Resource: "applications",
+}
+
+const (
+	// watchReconnectMinBackoff/MaxBackoff bound the jittered, exponentially
+	// growing backoff between watch reconnect attempts when the watch
+	// disconnects repeatedly.
+	watchReconnectMinBackoff = 1 * time.Second
+	watchReconnectMaxBackoff = 30 * time.Second
+	// watchReconnectFactor is how much the backoff grows after each
+	// consecutive failed reconnect attempt.
+	watchReconnectFactor = 2.0
+	// watchFallbackPollInterval is how often applicationWatcher falls back
+	// to a plain `argocd app list` while a watch connection is down.
+	watchFallbackPollInterval = 2 * time.Second
+)
+
+// applicationWatcher maintains a live view of every ArgoCD Application's
+// status by watching applications.argoproj.io, falling back to periodic
+// list-based reconciliation whenever the watch connection drops.
+type applicationWatcher struct {
+	client  dynamic.Interface
+	manager *Manager
+	verbose bool
+
+	// snapshots delivers a full map[name]AppStatus every time the watcher
+	// observes a change, so the consumer never needs to diff events itself.
+	snapshots chan map[string]AppStatus
+}
+
+// newApplicationWatcher creates a watcher whose Start publishes snapshots on
+// its snapshots channel until Start's ctx is cancelled.
+func newApplicationWatcher(client dynamic.Interface, manager *Manager, verbose bool) *applicationWatcher {
+	return &applicationWatcher{
+		client:    client,
+		manager:   manager,
+		verbose:   verbose,
+		snapshots: make(chan map[string]AppStatus, 1),
+	}
+}
+
+// Start runs the watch loop until ctx is cancelled and then returns
+// ctx.Err(); it never returns nil. Watch errors trigger the list-based
+// fallback rather than aborting.
+func (w *applicationWatcher) Start(ctx context.Context) error {
+	state := make(map[string]AppStatus)
+	backoff := watchReconnectMinBackoff
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		iface, err := w.client.Resource(applicationsGVR).Namespace("").Watch(ctx, metav1.ListOptions{})
+		if err != nil {
+			w.fallbackReconcile(ctx, state, backoff)
+			backoff = nextWatchBackoff(backoff)
+			continue
+		}
+
+		// A connection was established; reset the backoff so a later
+		// disconnect starts retrying quickly again.
+		backoff = watchReconnectMinBackoff
+		w.consume(ctx, iface, state)
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// The watch channel closed (disconnect) - reconcile via list while
+		// we wait to retry the watch, with escalating backoff between
+		// attempts.
+		w.fallbackReconcile(ctx, state, backoff)
+		backoff = nextWatchBackoff(backoff)
+	}
+}
+
+// nextWatchBackoff grows d by watchReconnectFactor, capped at
+// watchReconnectMaxBackoff.
+func nextWatchBackoff(d time.Duration) time.Duration {
+	next := time.Duration(float64(d) * watchReconnectFactor)
+	if next > watchReconnectMaxBackoff {
+		next = watchReconnectMaxBackoff
+	}
+	return next
+}
+
+// consume drains events from an open watch until it closes or ctx is done,
+// updating state and publishing a snapshot after every change.
+func (w *applicationWatcher) consume(ctx context.Context, iface kwatch.Interface, state map[string]AppStatus) {
+	defer iface.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-iface.ResultChan():
+			if !ok {
+				return
+			}
+
+			obj, ok := event.Object.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+
+			name := obj.GetName()
+			switch event.Type {
+			case kwatch.Deleted:
+				delete(state, name)
+			default: // Added, Modified
+				state[name] = appStatusFromUnstructured(obj)
+			}
+
+			w.publish(state)
+		}
+	}
+}
+
+// fallbackReconcile polls `argocd app list` every watchFallbackPollInterval,
+// keeping state current, for up to a jittered retryAfter before returning so
+// Start can retry the watch connection. Unlike wait.UntilWithContext, this
+// always returns once retryAfter has elapsed (rather than looping until ctx
+// is cancelled), so the caller's escalating backoff between reconnect
+// attempts actually takes effect.
+func (w *applicationWatcher) fallbackReconcile(ctx context.Context, state map[string]AppStatus, retryAfter time.Duration) {
+	poll := func() {
+		apps, err := w.manager.parseApplications(ctx, w.verbose)
+		if err == nil {
+			for name := range state {
+				delete(state, name)
+			}
+			for _, app := range apps {
+				state[app.Name] = app
+			}
+			w.publish(state)
+		}
+	}
+	poll()
+
+	deadline := time.NewTimer(wait.Jitter(retryAfter, 0.2))
+	defer deadline.Stop()
+	ticker := time.NewTicker(watchFallbackPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C:
+			return
+		case <-ticker.C:
+			poll()
+		}
+	}
+}
+
+// publish sends a copy of state on the snapshots channel, dropping the
+// oldest pending snapshot if the consumer hasn't read it yet.
+func (w *applicationWatcher) publish(state map[string]AppStatus) {
+	snapshot := make(map[string]AppStatus, len(state))
+	for k, v := range state {
+		snapshot[k] = v
+	}
+
+	select {
+	case <-w.snapshots:
+	default:
+	}
+	w.snapshots <- snapshot
+}
+
+// appStatusFromUnstructured parses an Application's health/sync/operation
+// status out of its unstructured representation, mirroring the fields
+// parseApplications extracts from `argocd app list -o json`.
+func appStatusFromUnstructured(obj *unstructured.Unstructured) AppStatus {
+	status := AppStatus{Name: obj.GetName()}
+
+	status.Health, _, _ = unstructured.NestedString(obj.Object, "status", "health", "status")
+	status.Sync, _, _ = unstructured.NestedString(obj.Object, "status", "sync", "status")
+	status.Phase, _, _ = unstructured.NestedString(obj.Object, "status", "operationState", "phase")
+	status.Message, _, _ = unstructured.NestedString(obj.Object, "status", "operationState", "message")
+
+	annotations := obj.GetAnnotations()
+	status.IgnoreExtraneous = annotations["argocd.argoproj.io/sync-options"] == "IgnoreExtraneous"
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		cm, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ctype, _ := cm["type"].(string)
+		cmsg, _ := cm["message"].(string)
+		status.Conditions = append(status.Conditions, AppCondition{Type: ctype, Message: cmsg})
+	}
+
+	resources, _, _ := unstructured.NestedSlice(obj.Object, "status", "resources")
+	for _, r := range resources {
+		rm, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		requiresPruning, _ := rm["requiresPruning"].(bool)
+		if requiresPruning {
+			status.PruningRequired++
+		}
+
+		res := AppResource{RequiresPruning: requiresPruning}
+		res.Kind, _ = rm["kind"].(string)
+		res.Name, _ = rm["name"].(string)
+		res.HookPhase, _ = rm["hookPhase"].(string)
+		if hm, ok := rm["health"].(map[string]interface{}); ok {
+			res.Health, _ = hm["status"].(string)
+		}
+		status.Resources = append(status.Resources, res)
+	}
+
+	return status
+}
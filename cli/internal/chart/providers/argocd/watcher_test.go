@@ -0,0 +1,231 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newApplicationObject(name string, annotations map[string]string, status map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": status,
+	}}
+	obj.SetName(name)
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+func TestAppStatusFromUnstructured_HealthSyncAndPhase(t *testing.T) {
+	obj := newApplicationObject("my-app", nil, map[string]interface{}{
+		"health": map[string]interface{}{"status": "Healthy"},
+		"sync":   map[string]interface{}{"status": "Synced"},
+		"operationState": map[string]interface{}{
+			"phase":   "Succeeded",
+			"message": "sync successful",
+		},
+	})
+
+	status := appStatusFromUnstructured(obj)
+
+	assert.Equal(t, "my-app", status.Name)
+	assert.Equal(t, "Healthy", status.Health)
+	assert.Equal(t, "Synced", status.Sync)
+	assert.Equal(t, "Succeeded", status.Phase)
+	assert.Equal(t, "sync successful", status.Message)
+}
+
+func TestAppStatusFromUnstructured_IgnoreExtraneousAnnotation(t *testing.T) {
+	obj := newApplicationObject("my-app", map[string]string{
+		"argocd.argoproj.io/sync-options": "IgnoreExtraneous",
+	}, nil)
+
+	status := appStatusFromUnstructured(obj)
+	assert.True(t, status.IgnoreExtraneous)
+}
+
+func TestAppStatusFromUnstructured_ConditionsAndResources(t *testing.T) {
+	obj := newApplicationObject("my-app", nil, map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "SyncError", "message": "boom"},
+		},
+		"resources": []interface{}{
+			map[string]interface{}{
+				"kind":            "Deployment",
+				"name":            "api",
+				"hookPhase":       "Running",
+				"requiresPruning": true,
+				"health":          map[string]interface{}{"status": "Degraded"},
+			},
+		},
+	})
+
+	status := appStatusFromUnstructured(obj)
+
+	assert.Equal(t, []AppCondition{{Type: "SyncError", Message: "boom"}}, status.Conditions)
+	assert.Equal(t, 1, status.PruningRequired)
+	assert.Equal(t, []AppResource{{
+		Kind:            "Deployment",
+		Name:            "api",
+		Health:          "Degraded",
+		HookPhase:       "Running",
+		RequiresPruning: true,
+	}}, status.Resources)
+}
+
+func TestAppStatusFromUnstructured_EmptyStatus(t *testing.T) {
+	obj := newApplicationObject("my-app", nil, nil)
+
+	status := appStatusFromUnstructured(obj)
+
+	assert.Equal(t, "my-app", status.Name)
+	assert.Empty(t, status.Health)
+	assert.Empty(t, status.Sync)
+	assert.Empty(t, status.Conditions)
+	assert.Empty(t, status.Resources)
+	assert.Equal(t, 0, status.PruningRequired)
+}
+
+func TestNextWatchBackoff(t *testing.T) {
+	d := watchReconnectMinBackoff
+	d = nextWatchBackoff(d)
+	assert.Equal(t, 2*watchReconnectMinBackoff, d)
+
+	for i := 0; i < 10; i++ {
+		d = nextWatchBackoff(d)
+	}
+	assert.Equal(t, watchReconnectMaxBackoff, d, "backoff must not exceed the configured cap")
+}
+
+func newFakeApplicationsClient() *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		applicationsGVR: "ApplicationList",
+	})
+}
+
+// waitForSnapshot drains w's snapshot channel until one satisfies match, or
+// fails the test after a few seconds.
+func waitForSnapshot(t *testing.T, ch <-chan map[string]AppStatus, match func(map[string]AppStatus) bool) map[string]AppStatus {
+	t.Helper()
+	deadline := time.After(3 * time.Second)
+	for {
+		select {
+		case s := <-ch:
+			if match(s) {
+				return s
+			}
+		case <-deadline:
+			t.Fatal("timed out waiting for a matching snapshot")
+			return nil
+		}
+	}
+}
+
+func TestApplicationWatcher_PublishesSnapshotsOnCreateAndDelete(t *testing.T) {
+	client := newFakeApplicationsClient()
+	w := newApplicationWatcher(client, &Manager{}, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- w.Start(ctx) }()
+
+	app := newFakeApplication("guestbook", "Healthy", "Synced", "")
+	_, err := client.Resource(applicationsGVR).Namespace("argocd").Create(ctx, app, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	snapshot := waitForSnapshot(t, w.snapshots, func(s map[string]AppStatus) bool { return len(s) == 1 })
+	assert.Equal(t, "Healthy", snapshot["guestbook"].Health)
+
+	err = client.Resource(applicationsGVR).Namespace("argocd").Delete(ctx, "guestbook", metav1.DeleteOptions{})
+	assert.NoError(t, err)
+
+	snapshot = waitForSnapshot(t, w.snapshots, func(s map[string]AppStatus) bool { return len(s) == 0 })
+	assert.Empty(t, snapshot)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after ctx cancellation")
+	}
+}
+
+func TestApplicationWatcher_ReconnectsAfterWatchDisconnect(t *testing.T) {
+	client := newFakeApplicationsClient()
+
+	first := watch.NewFake()
+	second := watch.NewFake()
+	watchers := []*watch.FakeWatcher{first, second}
+	call := 0
+	client.PrependWatchReactor("applications", func(clienttesting.Action) (bool, watch.Interface, error) {
+		w := watchers[call]
+		if call < len(watchers)-1 {
+			call++
+		}
+		return true, w, nil
+	})
+
+	w := newApplicationWatcher(client, &Manager{}, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = w.Start(ctx) }()
+
+	first.Add(newFakeApplication("guestbook", "Healthy", "Synced", ""))
+	snapshot := waitForSnapshot(t, w.snapshots, func(s map[string]AppStatus) bool { return len(s) == 1 })
+	assert.Equal(t, "Healthy", snapshot["guestbook"].Health)
+
+	// Simulate the watch connection dropping. Start must reconnect (via the
+	// second fake watcher) rather than getting stuck polling forever.
+	first.Stop()
+	second.Modify(newFakeApplication("guestbook", "Progressing", "OutOfSync", ""))
+
+	snapshot = waitForSnapshot(t, w.snapshots, func(s map[string]AppStatus) bool {
+		return s["guestbook"].Health == "Progressing"
+	})
+	assert.Equal(t, "Progressing", snapshot["guestbook"].Health)
+}
+
+func TestFallbackReconcile_ReturnsOnceRetryAfterElapses(t *testing.T) {
+	w := newApplicationWatcher(nil, &Manager{}, false)
+	state := make(map[string]AppStatus)
+
+	start := time.Now()
+	w.fallbackReconcile(context.Background(), state, 50*time.Millisecond)
+	elapsed := time.Since(start)
+
+	assert.GreaterOrEqual(t, elapsed, 40*time.Millisecond)
+	assert.Less(t, elapsed, 500*time.Millisecond,
+		"fallbackReconcile must return once retryAfter elapses instead of blocking until ctx is cancelled")
+}
+
+func TestFallbackReconcile_ReturnsPromptlyOnContextCancellation(t *testing.T) {
+	w := newApplicationWatcher(nil, &Manager{}, false)
+	state := make(map[string]AppStatus)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.fallbackReconcile(ctx, state, time.Minute)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("fallbackReconcile did not return promptly after ctx cancellation")
+	}
+}
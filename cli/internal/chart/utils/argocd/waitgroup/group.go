@@ -0,0 +1,102 @@
+// Package waitgroup provides a small RunnableGroup, modeled on
+// controller-runtime's manager.RunnableGroup, for coordinating several
+// concurrent waiters that must all reach readiness before a wait operation
+// can complete.
+package waitgroup
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pollInterval is how often Run checks whether every Runnable has reached
+// readiness. Runnables have no way to push a readiness event, so the Group
+// polls instead.
+const pollInterval = 50 * time.Millisecond
+
+// Runnable is a unit of work a Group starts concurrently and polls for
+// readiness.
+type Runnable interface {
+	// Start runs the runnable until ctx is cancelled or it hits a terminal
+	// condition, in which case it returns a non-nil error. Start blocks for
+	// the lifetime of the runnable; it does not return nil on success, since
+	// success is expressed through Ready() instead.
+	Start(ctx context.Context) error
+
+	// Ready reports whether this runnable has reached a steady, ready
+	// state. A Group's overall readiness gate flips once every registered
+	// Runnable's Ready() returns true at the same time.
+	Ready() bool
+}
+
+// Group starts a set of Runnables concurrently, gates overall readiness on
+// all of them being ready at once, and propagates the first error any of
+// them returns to the caller, cancelling the rest.
+type Group struct {
+	mu        sync.Mutex
+	started   bool
+	runnables []Runnable
+}
+
+// Add registers a Runnable to be started by Run. Add must be called before
+// Run; it panics if called afterwards.
+func (g *Group) Add(r Runnable) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.started {
+		panic("waitgroup: Add called after Run")
+	}
+	g.runnables = append(g.runnables, r)
+}
+
+// Run starts every registered Runnable concurrently and blocks until one of:
+//   - every Runnable's Ready() reports true at the same time (returns nil),
+//   - a Runnable's Start returns a non-nil error (propagated to the caller,
+//     and the rest of the group is cancelled), or
+//   - ctx is cancelled (returns ctx.Err()).
+func (g *Group) Run(ctx context.Context) error {
+	g.mu.Lock()
+	g.started = true
+	runnables := append([]Runnable(nil), g.runnables...)
+	g.mu.Unlock()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errChan := make(chan error, len(runnables))
+	for _, r := range runnables {
+		r := r
+		go func() {
+			errChan <- r.Start(runCtx)
+		}()
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-runCtx.Done():
+			return runCtx.Err()
+		case err := <-errChan:
+			if err != nil {
+				cancel()
+				return err
+			}
+		case <-ticker.C:
+			if allReady(runnables) {
+				return nil
+			}
+		}
+	}
+}
+
+func allReady(runnables []Runnable) bool {
+	for _, r := range runnables {
+		if !r.Ready() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package waitgroup
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRunnable is a Runnable whose readiness and return error are controlled
+// by the test.
+type fakeRunnable struct {
+	ready   atomic.Bool
+	startAt int32
+	err     error
+}
+
+func (r *fakeRunnable) Start(ctx context.Context) error {
+	atomic.AddInt32(&r.startAt, 1)
+	<-ctx.Done()
+	if r.err != nil {
+		return r.err
+	}
+	return ctx.Err()
+}
+
+func (r *fakeRunnable) Ready() bool {
+	return r.ready.Load()
+}
+
+func TestGroup_RunReturnsOnceAllReady(t *testing.T) {
+	a := &fakeRunnable{}
+	b := &fakeRunnable{}
+
+	g := &Group{}
+	g.Add(a)
+	g.Add(b)
+
+	done := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { done <- g.Run(ctx) }()
+
+	a.ready.Store(true)
+	time.Sleep(3 * pollInterval)
+	b.ready.Store(true)
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return once every runnable was ready")
+	}
+}
+
+func TestGroup_RunPropagatesFirstError(t *testing.T) {
+	boom := errors.New("boom")
+	a := &fakeRunnable{err: boom}
+	b := &fakeRunnable{}
+
+	g := &Group{}
+	g.Add(a)
+	g.Add(b)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- g.Run(ctx) }()
+
+	// fakeRunnable only returns once its context is cancelled, so cancel to
+	// unblock both and let Run propagate whichever error surfaces first.
+	cancel()
+
+	select {
+	case err := <-errCh:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancellation")
+	}
+}
+
+func TestGroup_RunReturnsContextError(t *testing.T) {
+	a := &fakeRunnable{}
+
+	g := &Group{}
+	g.Add(a)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := g.Run(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGroup_AddAfterRunPanics(t *testing.T) {
+	g := &Group{}
+	g.Add(&fakeRunnable{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = g.Run(ctx)
+
+	assert.Panics(t, func() { g.Add(&fakeRunnable{}) })
+}
@@ -0,0 +1,51 @@
+// Package config holds the shared configuration types passed between the
+// chart provider packages during install/upgrade operations.
+package config
+
+import "time"
+
+// StallPolicy controls what WaitForApplications does when an application
+// stalls in a non-Healthy state for longer than PerAppStallTimeout.
+type StallPolicy string
+
+const (
+	// StallPolicyWarn logs a diagnosis for a stalled application and keeps
+	// waiting. This is the default (zero-value) behavior.
+	StallPolicyWarn StallPolicy = "warn"
+	// StallPolicyStrict aborts the wait with an error once an application
+	// has been stalled longer than PerAppStallTimeout.
+	StallPolicyStrict StallPolicy = "strict"
+)
+
+// ChartInstallConfig controls how a chart (and its ArgoCD applications) is
+// installed and how the install waits for the cluster to settle.
+type ChartInstallConfig struct {
+	// DryRun skips any waiting and reports success immediately. Used by tests
+	// and `--dry-run` invocations.
+	DryRun bool
+
+	// Verbose enables periodic progress logging in addition to the spinner.
+	Verbose bool
+
+	// Silent disables the interactive spinner in favor of a single info line,
+	// for non-interactive/CI environments.
+	Silent bool
+
+	// AllowEventualConsistency opts out of fail-fast behavior: by default
+	// (false, the zero value) WaitForApplications aborts as soon as any
+	// application reports a Failed/Error operation phase or a
+	// SyncError/ComparisonError condition, instead of waiting for the full
+	// timeout. Set to true for eventually-consistent bootstraps where
+	// applications are expected to flap before settling.
+	AllowEventualConsistency bool
+
+	// PerAppStallTimeout is how long an application may sit in a
+	// non-Healthy state without a status transition before it's considered
+	// stalled. Defaults to 15 minutes when zero.
+	PerAppStallTimeout time.Duration
+
+	// StallPolicy controls what happens once an application is considered
+	// stalled: StallPolicyWarn (the default) logs a diagnosis and keeps
+	// waiting, StallPolicyStrict aborts the wait.
+	StallPolicy StallPolicy
+}